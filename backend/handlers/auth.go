@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/mayajenk/CEN3031/models"
+	"github.com/wader/gormstore/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const sessionUserIDKey = "userID"
+
+// Login verifies a username/password pair and starts a session for the user.
+func Login(store *gormstore.Store, db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var body struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+
+		var user models.User
+		err := db.Where("username = ?", body.Username).First(&user).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				sendError("Invalid username or password", http.StatusUnauthorized, w)
+				return
+			}
+			sendError("Error retrieving user", http.StatusInternalServerError, w)
+			return
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(body.Password)); err != nil {
+			sendError("Invalid username or password", http.StatusUnauthorized, w)
+			return
+		}
+
+		session, err := store.Get(r, "session")
+		if err != nil {
+			sendError("Error starting session", http.StatusInternalServerError, w)
+			return
+		}
+
+		session.Values[sessionUserIDKey] = user.ID
+		if err := session.Save(r, w); err != nil {
+			sendError("Error starting session", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":       user.ID,
+			"username": user.Username,
+			"is_tutor": user.IsTutor,
+		})
+	}
+}
+
+// Logout ends the caller's session.
+func Logout(store *gormstore.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session, err := store.Get(r, "session")
+		if err != nil {
+			sendError("Error retrieving session", http.StatusUnauthorized, w)
+			return
+		}
+
+		session.Options.MaxAge = -1
+		if err := session.Save(r, w); err != nil {
+			sendError("Error ending session", http.StatusInternalServerError, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// RequireAuth rejects any request that doesn't carry a valid session with a
+// userID, before handing off to next.
+func RequireAuth(store *gormstore.Store, db *gorm.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := sessionUser(store, db, r); !ok {
+				sendError("Unauthorized", http.StatusUnauthorized, w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
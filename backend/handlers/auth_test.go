@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+	"github.com/wader/gormstore/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// setupTestStore builds a session store backed by db. It must share the
+// same connection as the test's user-table operations (rather than opening
+// a second connection to the shared-cache in-memory DB) - otherwise the
+// store's writes block forever on the lock held by the caller's still-open
+// transaction.
+func setupTestStore(db *gorm.DB) *gormstore.Store {
+	return gormstore.New(db, []byte("test-session-key"))
+}
+
+func TestLoginHandler(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+	store := setupTestStore(tx)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	user := models.User{Username: "testuser", Password: string(hashed)}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/login", Login(store, tx)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "testuser", "password": "password"})
+	req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if rr.Header().Get("Set-Cookie") == "" {
+		t.Errorf("Expected a session cookie to be set")
+	}
+}
+
+func TestLoginHandlerWrongPassword(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+	store := setupTestStore(tx)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	user := models.User{Username: "testuser", Password: string(hashed)}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/login", Login(store, tx)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "testuser", "password": "wrong"})
+	req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestLoginHandlerMissingUser(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+	store := setupTestStore(tx)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/login", Login(store, tx)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "nobody", "password": "password"})
+	req, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireAuthRejectsExpiredSession(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+	store := setupTestStore(tx)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	user := models.User{Username: "testuser", Password: string(hashed)}
+	tx.Create(&user)
+
+	protected := RequireAuth(store, tx)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/login", Login(store, tx)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "testuser", "password": "password"})
+	loginReq, _ := http.NewRequest("POST", "/api/login", bytes.NewBuffer(reqBody))
+	loginRR := httptest.NewRecorder()
+	r.ServeHTTP(loginRR, loginReq)
+
+	// Push the stored session's expiry into the past, as if it had timed out.
+	tx.Table("sessions").Where("1 = 1").Update("expires_at", time.Now().Add(-time.Hour))
+
+	req, _ := http.NewRequest("GET", "/api/protected", nil)
+	req.Header.Set("Cookie", loginRR.Header().Get("Set-Cookie"))
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
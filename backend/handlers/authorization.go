@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+	"github.com/wader/gormstore/v2"
+	"gorm.io/gorm"
+)
+
+// sessionUser loads the User for the caller's session, or returns ok=false
+// if there isn't a valid one.
+func sessionUser(store *gormstore.Store, db *gorm.DB, r *http.Request) (models.User, bool) {
+	session, err := store.Get(r, "session")
+	if err != nil {
+		return models.User{}, false
+	}
+
+	userID, ok := session.Values[sessionUserIDKey]
+	if !ok {
+		return models.User{}, false
+	}
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return models.User{}, false
+	}
+
+	return user, true
+}
+
+// RequireSelfOrAdmin allows a request through only if the caller is the
+// user named by the {id} mux var, or is an admin. It's meant for endpoints
+// like UpdateUser/DeleteUser that act on a specific account.
+func RequireSelfOrAdmin(store *gormstore.Store, db *gorm.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := sessionUser(store, db, r)
+			if !ok {
+				sendError("Unauthorized", http.StatusUnauthorized, w)
+				return
+			}
+
+			targetID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+			if err != nil {
+				sendError("Invalid user id", http.StatusBadRequest, w)
+				return
+			}
+
+			if !user.IsAdmin && uint64(user.ID) != targetID {
+				sendError("Forbidden", http.StatusForbidden, w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole allows a request through only if the caller's Role is one of
+// roles.
+func RequireRole(store *gormstore.Store, db *gorm.DB, roles ...models.Role) func(http.Handler) http.Handler {
+	allowed := make(map[models.Role]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := sessionUser(store, db, r)
+			if !ok {
+				sendError("Unauthorized", http.StatusUnauthorized, w)
+				return
+			}
+
+			if !allowed[user.Role()] {
+				sendError("Forbidden", http.StatusForbidden, w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
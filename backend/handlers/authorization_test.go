@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+)
+
+func TestRequireSelfOrAdmin(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+	store := setupTestStore(tx)
+
+	owner := models.User{Username: "owner"}
+	other := models.User{Username: "other"}
+	admin := models.User{Username: "admin", IsAdmin: true}
+	tx.Create(&owner)
+	tx.Create(&other)
+	tx.Create(&admin)
+
+	protected := RequireSelfOrAdmin(store, tx)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	router := mux.NewRouter()
+	router.Handle("/api/users/{id}", protected)
+
+	tests := []struct {
+		name       string
+		callerID   uint
+		wantStatus int
+	}{
+		{"owner", owner.ID, http.StatusOK},
+		{"other user", other.ID, http.StatusForbidden},
+		{"admin", admin.ID, http.StatusOK},
+		{"unauthenticated", 0, http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			targetPath := "/api/users/" + strconv.FormatUint(uint64(owner.ID), 10)
+			req := httptest.NewRequest("GET", targetPath, nil)
+			rr := httptest.NewRecorder()
+
+			if tc.callerID != 0 {
+				session, _ := store.Get(req, "session")
+				session.Values[sessionUserIDKey] = tc.callerID
+				session.Save(req, rr)
+				req = httptest.NewRequest("GET", targetPath, nil)
+				req.Header.Set("Cookie", rr.Header().Get("Set-Cookie"))
+			}
+
+			rr = httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tc.wantStatus {
+				t.Errorf("%s: got status %v want %v", tc.name, rr.Code, tc.wantStatus)
+			}
+		})
+	}
+}
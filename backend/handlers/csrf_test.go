@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireCSRFTokenAllowsFirstRequestWithNoCookie(t *testing.T) {
+	protected := RequireCSRFToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/login", nil)
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("first request with no CSRF cookie should be let through: got status %v", rr.Code)
+	}
+	if rr.Header().Get("Set-Cookie") == "" {
+		t.Errorf("expected a CSRF cookie to be minted on the first request")
+	}
+}
+
+func TestRequireCSRFTokenRejectsMismatchedHeader(t *testing.T) {
+	protected := RequireCSRFToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/login", nil)
+	req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "real-token"})
+	req.Header.Set(csrfHeaderName, "wrong-token")
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("mismatched CSRF header should be rejected: got status %v", rr.Code)
+	}
+}
+
+func TestRequireCSRFTokenAllowsMatchingHeader(t *testing.T) {
+	protected := RequireCSRFToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/login", nil)
+	req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "real-token"})
+	req.Header.Set(csrfHeaderName, "real-token")
+	rr := httptest.NewRecorder()
+	protected.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("matching CSRF header should be let through: got status %v", rr.Code)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mayajenk/CEN3031/mail"
+	"github.com/mayajenk/CEN3031/models"
+	"github.com/wader/gormstore/v2"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	passwordResetTokenTTL   = 30 * time.Minute
+	passwordResetTokenBytes = 32
+)
+
+// passwordResetIPLimiter and passwordResetAccountLimiter bound how often a
+// password reset can be requested from a given IP or for a given username or
+// email (keyed by the identifier as supplied in the request body), to
+// make it impractical to use the endpoint for username enumeration or to
+// spam a victim's inbox.
+var (
+	passwordResetIPLimiter      = newRateLimiter(10, time.Hour)
+	passwordResetAccountLimiter = newRateLimiter(3, time.Hour)
+)
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RequestPasswordReset issues a password reset token for the account
+// identified by the request body's username or email, and emails it to the
+// account's address via sender. Apart from the per-IP rate limit (429), a
+// malformed body (400) and internal failures while issuing a token (500),
+// it responds 200 with the same generic message, whether or not the account
+// exists (or has no email on file), so the endpoint can't be used to
+// enumerate usernames.
+func RequestPasswordReset(db *gorm.DB, sender mail.Sender) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		ip, _, splitErr := net.SplitHostPort(r.RemoteAddr)
+		if splitErr != nil {
+			ip = r.RemoteAddr
+		}
+		if !passwordResetIPLimiter.Allow(ip) {
+			sendError("Too many requests", http.StatusTooManyRequests, w)
+			return
+		}
+
+		var body struct {
+			Username string `json:"username"`
+			Email    string `json:"email"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+
+		const genericResponse = "If that account exists, a password reset email has been sent."
+
+		identifier := body.Username
+		if identifier == "" {
+			identifier = body.Email
+		}
+		if !passwordResetAccountLimiter.Allow(identifier) {
+			json.NewEncoder(w).Encode(map[string]string{"message": genericResponse})
+			return
+		}
+
+		var user models.User
+		var err error
+		switch {
+		case body.Username != "":
+			err = db.Where("username = ?", body.Username).First(&user).Error
+		case body.Email != "":
+			err = db.Where("email = ?", body.Email).First(&user).Error
+		default:
+			err = gorm.ErrRecordNotFound
+		}
+		if err != nil || user.Email == "" {
+			json.NewEncoder(w).Encode(map[string]string{"message": genericResponse})
+			return
+		}
+
+		raw := make([]byte, passwordResetTokenBytes)
+		if _, err := rand.Read(raw); err != nil {
+			sendError("Failed to generate reset token", http.StatusInternalServerError, w)
+			return
+		}
+		token := base64.RawURLEncoding.EncodeToString(raw)
+
+		resetToken := models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashResetToken(token),
+			ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+		}
+		if err := db.Create(&resetToken).Error; err != nil {
+			sendError("Failed to create reset token", http.StatusInternalServerError, w)
+			return
+		}
+
+		sender.Send(user.Email, "Reset your password",
+			"Use this code to reset your password: "+token+"\nIt expires in 30 minutes.")
+
+		json.NewEncoder(w).Encode(map[string]string{"message": genericResponse})
+	}
+}
+
+// ValidatePasswordResetToken reports whether the token passed as ?token=
+// is a valid, unused, unexpired reset token, without consuming it.
+func ValidatePasswordResetToken(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			sendError("Missing token", http.StatusBadRequest, w)
+			return
+		}
+
+		var resetToken models.PasswordResetToken
+		err := db.Where("token_hash = ?", hashResetToken(token)).First(&resetToken).Error
+		if err != nil || resetToken.Used || time.Now().After(resetToken.ExpiresAt) {
+			sendError("Invalid or expired token", http.StatusBadRequest, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]bool{"valid": true})
+	}
+}
+
+// ConfirmPasswordReset consumes a reset token, sets the account's new
+// password through the same hashing path as UpdateUser, and invalidates the
+// account's active sessions in sessionDB.
+func ConfirmPasswordReset(store *gormstore.Store, db *gorm.DB, sessionDB *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var body struct {
+			Token    string `json:"token"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+
+		var resetToken models.PasswordResetToken
+		err := db.Where("token_hash = ?", hashResetToken(body.Token)).First(&resetToken).Error
+		if err != nil || resetToken.Used || time.Now().After(resetToken.ExpiresAt) {
+			sendError("Invalid or expired token", http.StatusBadRequest, w)
+			return
+		}
+
+		var user models.User
+		if err := db.First(&user, resetToken.UserID).Error; err != nil {
+			sendError("Invalid or expired token", http.StatusBadRequest, w)
+			return
+		}
+
+		hashed, err := bcrypt.GenerateFromPassword([]byte(body.Password), bcrypt.DefaultCost)
+		if err != nil {
+			sendError("Failed to hash password", http.StatusInternalServerError, w)
+			return
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&user).Update("Password", string(hashed)).Error; err != nil {
+				return err
+			}
+			resetToken.Used = true
+			return tx.Save(&resetToken).Error
+		})
+		if err != nil {
+			sendError("Failed to reset password", http.StatusInternalServerError, w)
+			return
+		}
+
+		invalidateUserSessions(sessionDB, user.ID)
+
+		json.NewEncoder(w).Encode(map[string]string{"message": "Password has been reset"})
+	}
+}
+
+// invalidateUserSessions deletes every gormstore session belonging to
+// userID. gormstore persists session.Values as a gob-encoded blob rather
+// than individual indexed columns, so there's no userID column to filter
+// on directly; instead we match the serialized form of the "userID" key
+// that Login stores in session.Values.
+func invalidateUserSessions(sessionDB *gorm.DB, userID uint) {
+	marker := fmt.Sprintf("userID%d", userID)
+	sessionDB.Table("sessions").Where("data LIKE ?", "%"+marker+"%").Delete(&struct{}{})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/mail"
+	"github.com/mayajenk/CEN3031/models"
+)
+
+func TestRequestPasswordResetHandler(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser", Password: "password", Email: "testuser@example.com"}
+	tx.Create(&user)
+
+	sender := mail.NewFakeSender()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/password-reset", RequestPasswordReset(tx, sender)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "testuser"})
+	req, _ := http.NewRequest("POST", "/api/password-reset", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if len(sender.Sent) != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", len(sender.Sent))
+	}
+	if sender.Sent[0].To != "testuser@example.com" {
+		t.Errorf("Email sent to wrong address: got %v want %v", sender.Sent[0].To, "testuser@example.com")
+	}
+
+	var tokens []models.PasswordResetToken
+	tx.Where("user_id = ?", user.ID).Find(&tokens)
+	if len(tokens) != 1 {
+		t.Fatalf("Expected one reset token to be created, got %d", len(tokens))
+	}
+}
+
+func TestRequestPasswordResetHandlerUnknownUserRespondsIdentically(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	sender := mail.NewFakeSender()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/password-reset", RequestPasswordReset(tx, sender)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "nobody"})
+	req, _ := http.NewRequest("POST", "/api/password-reset", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if len(sender.Sent) != 0 {
+		t.Errorf("Expected no email to be sent for unknown user, got %d", len(sender.Sent))
+	}
+}
+
+func TestRequestPasswordResetHandlerNoEmailOnFileRespondsIdentically(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser", Password: "password"}
+	tx.Create(&user)
+
+	sender := mail.NewFakeSender()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/password-reset", RequestPasswordReset(tx, sender)).Methods("POST")
+
+	reqBody, _ := json.Marshal(map[string]string{"username": "testuser"})
+	req, _ := http.NewRequest("POST", "/api/password-reset", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if len(sender.Sent) != 0 {
+		t.Errorf("Expected no email to be sent when the account has no email on file, got %d", len(sender.Sent))
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+	"gorm.io/gorm"
+)
+
+const maxProfilePictureSize = 5 << 20 // 5 MiB
+
+var allowedProfilePictureTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// profilePictureDir is where uploaded profile pictures are stored. It's a
+// var rather than an env lookup so tests can point it at a temp directory.
+var profilePictureDir = "uploads"
+
+// UploadProfilePicture stores a user's profile picture after validating its
+// size and sniffing its content type, and records its filename on the user
+// so GetProfilePicture can serve it back.
+func UploadProfilePicture(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		userID := mux.Vars(r)["id"]
+
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil {
+			sendError("Error retrieving user", http.StatusNotFound, w)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxProfilePictureSize)
+		if err := r.ParseMultipartForm(maxProfilePictureSize); err != nil {
+			sendError("File too large", http.StatusRequestEntityTooLarge, w)
+			return
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			sendError("Missing file", http.StatusBadRequest, w)
+			return
+		}
+		defer file.Close()
+
+		sniff := make([]byte, 512)
+		n, err := io.ReadFull(file, sniff)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			sendError("Error reading file", http.StatusInternalServerError, w)
+			return
+		}
+		sniff = sniff[:n]
+		contentType := http.DetectContentType(sniff)
+
+		ext, ok := allowedProfilePictureTypes[contentType]
+		if !ok {
+			sendError("Unsupported file type", http.StatusUnsupportedMediaType, w)
+			return
+		}
+
+		if err := os.MkdirAll(profilePictureDir, 0755); err != nil {
+			sendError("Error saving file", http.StatusInternalServerError, w)
+			return
+		}
+
+		filename := uuid.NewString() + ext
+		dest, err := os.Create(filepath.Join(profilePictureDir, filename))
+		if err != nil {
+			sendError("Error saving file", http.StatusInternalServerError, w)
+			return
+		}
+		defer dest.Close()
+
+		if _, err := dest.Write(sniff); err != nil {
+			sendError("Error saving file", http.StatusInternalServerError, w)
+			return
+		}
+		if _, err := io.Copy(dest, file); err != nil {
+			sendError("Error saving file", http.StatusInternalServerError, w)
+			return
+		}
+
+		if err := db.Model(&user).Update("ProfilePictureURL", filename).Error; err != nil {
+			sendError("Error saving file", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"profile_picture_url": "/api/users/" + userID + "/picture",
+		})
+	}
+}
+
+// GetProfilePicture serves a user's stored profile picture.
+func GetProfilePicture(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := mux.Vars(r)["id"]
+
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil || user.ProfilePictureURL == "" {
+			sendError("No profile picture", http.StatusNotFound, w)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join(profilePictureDir, user.ProfilePictureURL))
+		if err != nil {
+			sendError("No profile picture", http.StatusNotFound, w)
+			return
+		}
+
+		sum := sha256.Sum256(data)
+		etag := `"` + hex.EncodeToString(sum[:]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", http.DetectContentType(data))
+		io.Copy(w, bytes.NewReader(data))
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+)
+
+// a minimal valid 1x1 PNG
+var testPNG = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89, 0x00, 0x00, 0x00,
+	0x0a, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x00, 0x01, 0x00, 0x00,
+	0x05, 0x00, 0x01, 0x0d, 0x0a, 0x2d, 0xb4, 0x00, 0x00, 0x00, 0x00, 0x49,
+	0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+}
+
+func multipartUploadRequest(t *testing.T, userID uint, field, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if field != "" {
+		part, err := writer.CreateFormFile(field, filename)
+		if err != nil {
+			t.Fatalf("CreateFormFile: %v", err)
+		}
+		part.Write(content)
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/api/users/%d/picture", userID), &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadProfilePictureHandler(t *testing.T) {
+	dir := t.TempDir()
+	prev := profilePictureDir
+	profilePictureDir = dir
+	defer func() { profilePictureDir = prev }()
+
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser"}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users/{id}/picture", UploadProfilePicture(tx)).Methods("POST")
+
+	req := multipartUploadRequest(t, user.ID, "file", "avatar.png", testPNG)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Handler returned wrong status code: got %v want %v, body: %s", status, http.StatusOK, rr.Body.String())
+	}
+
+	var resp map[string]string
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp["profile_picture_url"] == "" {
+		t.Errorf("Expected a profile_picture_url in response")
+	}
+
+	var updated models.User
+	tx.First(&updated, user.ID)
+	if updated.ProfilePictureURL == "" {
+		t.Errorf("Expected ProfilePictureURL to be persisted")
+	}
+}
+
+func TestUploadProfilePictureHandlerWrongMIME(t *testing.T) {
+	dir := t.TempDir()
+	prev := profilePictureDir
+	profilePictureDir = dir
+	defer func() { profilePictureDir = prev }()
+
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser"}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users/{id}/picture", UploadProfilePicture(tx)).Methods("POST")
+
+	req := multipartUploadRequest(t, user.ID, "file", "avatar.txt", []byte("not an image"))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnsupportedMediaType {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestUploadProfilePictureHandlerMissingField(t *testing.T) {
+	dir := t.TempDir()
+	prev := profilePictureDir
+	profilePictureDir = dir
+	defer func() { profilePictureDir = prev }()
+
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser"}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users/{id}/picture", UploadProfilePicture(tx)).Methods("POST")
+
+	req := multipartUploadRequest(t, user.ID, "", "", nil)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestUploadProfilePictureHandlerOversized(t *testing.T) {
+	dir := t.TempDir()
+	prev := profilePictureDir
+	profilePictureDir = dir
+	defer func() { profilePictureDir = prev }()
+
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	user := models.User{Username: "testuser"}
+	tx.Create(&user)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users/{id}/picture", UploadProfilePicture(tx)).Methods("POST")
+
+	oversized := make([]byte, maxProfilePictureSize+1)
+	req := multipartUploadRequest(t, user.ID, "file", "avatar.png", oversized)
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusRequestEntityTooLarge && status != http.StatusUnsupportedMediaType {
+		t.Errorf("Handler returned wrong status code: got %v want %v or %v", status, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType)
+	}
+}
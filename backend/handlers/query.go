@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// columnBool marks a filterColumns entry as a boolean column, so
+// applyFilters parses its query value instead of binding the raw string -
+// gorm/sqlite store bool columns as integer 0/1, which "true"/"false" never
+// equals.
+const columnBool = "bool"
+
+// applyFilters adds an equality Where clause for every query parameter
+// whose name is a key in allowedColumns, ignoring anything else. This keeps
+// list endpoints from turning arbitrary query params into SQL. A column
+// whose value is columnBool has its query value parsed as a bool before
+// binding; any other value (including "") binds the raw string.
+func applyFilters(db *gorm.DB, values url.Values, allowedColumns map[string]string) *gorm.DB {
+	for column, kind := range allowedColumns {
+		raw := values.Get(column)
+		if raw == "" {
+			continue
+		}
+
+		var v any = raw
+		if kind == columnBool {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				continue
+			}
+			v = parsed
+		}
+
+		db = db.Where(fmt.Sprintf("%s = ?", column), v)
+	}
+	return db
+}
+
+// applySort orders db by the ?sort= query param, restricted to an allowlist
+// of columns so callers can't sort (or probe) by arbitrary expressions. A
+// leading "-" sorts descending; otherwise ascending. Unknown columns are
+// silently ignored.
+func applySort(db *gorm.DB, sort string, allowedColumns map[string]bool) *gorm.DB {
+	if sort == "" {
+		return db
+	}
+
+	direction := "ASC"
+	column := sort
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		column = sort[1:]
+	}
+
+	if !allowedColumns[column] {
+		return db
+	}
+	return db.Order(fmt.Sprintf("%s %s", column, direction))
+}
+
+// pagination holds a parsed, bounds-checked limit/offset pair for a list
+// endpoint.
+type pagination struct {
+	limit  int
+	offset int
+}
+
+// parsePagination reads ?limit= and ?offset= from values, falling back to
+// defaultLimit and clamping to [1, maxLimit].
+func parsePagination(values url.Values, defaultLimit, maxLimit int) pagination {
+	limit := defaultLimit
+	if raw := values.Get("limit"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset := 0
+	if raw := values.Get("offset"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	return pagination{limit: limit, offset: offset}
+}
+
+// setPaginationHeaders sets X-Total-Count and an RFC 5988 Link header
+// (first/prev/next/last, as applicable) describing the page relative to
+// the request's own URL and total.
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, p pagination, total int64) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	linkFor := func(offset int) string {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(p.limit))
+		q.Set("offset", strconv.Itoa(offset))
+		u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+		return u.String()
+	}
+
+	var links []string
+	links = append(links, fmt.Sprintf(`<%s>; rel="first"`, linkFor(0)))
+	if p.offset > 0 {
+		prev := p.offset - p.limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, linkFor(prev)))
+	}
+	if int64(p.offset+p.limit) < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, linkFor(p.offset+p.limit)))
+	}
+	if total > 0 {
+		lastOffset := (int(total-1) / p.limit) * p.limit
+		links = append(links, fmt.Sprintf(`<%s>; rel="last"`, linkFor(lastOffset)))
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
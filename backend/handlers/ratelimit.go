@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a simple sliding-window limiter keyed by an arbitrary string
+// (an IP address, a username, ...): a key may make at most limit requests
+// within any span of window. It is intentionally in-process and
+// non-distributed, which is sufficient for the single-instance deployment
+// this service currently runs as.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, hits: make(map[string][]time.Time)}
+}
+
+// Allow reports whether another request for key is permitted, recording it
+// if so.
+func (r *rateLimiter) Allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-r.window)
+
+	hits := r.hits[key][:0]
+	for _, t := range r.hits[key] {
+		if t.After(cutoff) {
+			hits = append(hits, t)
+		}
+	}
+
+	if len(hits) >= r.limit {
+		r.hits[key] = hits
+		return false
+	}
+
+	r.hits[key] = append(hits, now)
+	return true
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+	"gorm.io/gorm"
+)
+
+// AddSubject adds a subject (creating it if it doesn't already exist) to the
+// given user's list of subjects.
+func AddSubject(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		userID := mux.Vars(r)["id"]
+
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil {
+			sendError("Error retrieving user", http.StatusNotFound, w)
+			return
+		}
+
+		var subject models.Subject
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&subject); err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+
+		var existing models.Subject
+		result := db.Where("name = ?", subject.Name).First(&existing)
+		if result.Error == nil {
+			subject = existing
+		} else if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			sendError("Error retrieving subject", http.StatusInternalServerError, w)
+			return
+		} else if err := db.Create(&subject).Error; err != nil {
+			sendError("Error creating subject", http.StatusInternalServerError, w)
+			return
+		}
+
+		if err := db.Model(&user).Association("Subjects").Append(&subject); err != nil {
+			sendError("Error adding subject to user", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(subject)
+	}
+}
@@ -73,7 +73,7 @@ func TestAddSubjectHandler(t *testing.T) {
 	}
 
 	// Check that the existing subject was added to the user's list of subjects
-	db.Model(&user).Association("Subjects").Find(&existingSubject)
+	tx.Model(&user).Association("Subjects").Find(&existingSubject)
 	if existingSubject.ID == 0 {
 		t.Errorf("Existing subject was not added to user's subjects list")
 	}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"github.com/glebarez/sqlite"
+	"github.com/mayajenk/CEN3031/models"
+	"gorm.io/gorm"
+)
+
+// setupTestEnv opens an in-memory test database with all models migrated.
+func setupTestEnv() *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect database")
+	}
+
+	db.AutoMigrate(&models.User{}, &models.Subject{}, &models.Connection{}, &models.Review{}, &models.AvailabilitySlot{}, &models.PasswordResetToken{})
+
+	return db
+}
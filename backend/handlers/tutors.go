@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+	"gorm.io/gorm"
+)
+
+// TutorSearchResult is the response shape for SearchTutors: a tutor's
+// public profile, plus their average rating across all reviews (which
+// isn't a stored column, so it's computed per-search rather than living on
+// models.User).
+type TutorSearchResult struct {
+	models.UserDTO
+	AverageRating float64 `json:"average_rating"`
+}
+
+// tutorRating is what SearchTutors scans its filtering/sorting query into:
+// just the id and the computed average_rating column, used to work out
+// which users matched and in what order. It's deliberately not used for the
+// actual Preload - querying into a wrapper struct instead of models.User
+// directly breaks GORM's many2many foreign-key inference, which derives the
+// join column name from the queried type rather than from models.User.
+type tutorRating struct {
+	ID            uint
+	AverageRating float64 `gorm:"column:average_rating"`
+}
+
+// SearchTutors lists tutors, optionally filtered by subject, minimum
+// average rating, and availability during a time window, and sorted by
+// ?sort= (currently only "rating_desc" is recognized; anything else sorts
+// by username).
+//
+// ?available_from and ?available_to are RFC 3339 timestamps. Since
+// AvailabilitySlot is a weekly-recurring schedule rather than a calendar of
+// specific dates, only the day-of-week of available_from and the
+// time-of-day of both ends, taken in UTC, are used to match against slots,
+// and a tutor matches if they have a slot that fully contains the
+// [available_from, available_to) time-of-day window on that day.
+func SearchTutors(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+
+		avgRating := db.Model(&models.Review{}).Select("AVG(rating)").Where("reviews.user_id = users.id")
+
+		base := db.Model(&models.User{}).
+			Select("users.id, (?) AS average_rating", avgRating).
+			Where("users.is_tutor = ?", true).
+			Group("users.id")
+
+		if subject := query.Get("subject"); subject != "" {
+			base = base.Joins("JOIN user_subjects ON user_subjects.user_id = users.id").
+				Joins("JOIN subjects ON subjects.id = user_subjects.subject_id").
+				Where("subjects.name = ?", subject)
+		}
+
+		if raw := query.Get("min_rating"); raw != "" {
+			minRating, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				sendError("Invalid min_rating", http.StatusBadRequest, w)
+				return
+			}
+			base = base.Having("average_rating >= ?", minRating)
+		}
+
+		from, to, err := parseAvailabilityWindow(query.Get("available_from"), query.Get("available_to"))
+		if err != nil {
+			sendError("Invalid available_from/available_to", http.StatusBadRequest, w)
+			return
+		}
+		if from != nil && to != nil {
+			fromUTC, toUTC := from.UTC(), to.UTC()
+			dayOfWeek := int(fromUTC.Weekday())
+			fromMinute := fromUTC.Hour()*60 + fromUTC.Minute()
+			toMinute := toUTC.Hour()*60 + toUTC.Minute()
+			base = base.Joins("JOIN availability_slots ON availability_slots.tutor_id = users.id AND availability_slots.deleted_at IS NULL").
+				Where("availability_slots.day_of_week = ? AND availability_slots.start_minute <= ? AND availability_slots.end_minute >= ?",
+					dayOfWeek, fromMinute, toMinute)
+		}
+
+		if query.Get("sort") == "rating_desc" {
+			base = base.Order("average_rating DESC")
+		} else {
+			base = base.Order("users.username ASC")
+		}
+
+		var ratings []tutorRating
+		if err := base.Scan(&ratings).Error; err != nil {
+			sendError("Error searching tutors", http.StatusInternalServerError, w)
+			return
+		}
+
+		ids := make([]uint, len(ratings))
+		avgByID := make(map[uint]float64, len(ratings))
+		for i, rating := range ratings {
+			ids[i] = rating.ID
+			avgByID[rating.ID] = rating.AverageRating
+		}
+
+		var users []models.User
+		if len(ids) > 0 {
+			err := db.Preload("Subjects").Preload("Reviews").Preload("Availability").
+				Where("id IN ?", ids).Find(&users).Error
+			if err != nil {
+				sendError("Error searching tutors", http.StatusInternalServerError, w)
+				return
+			}
+		}
+		userByID := make(map[uint]models.User, len(users))
+		for _, user := range users {
+			userByID[user.ID] = user
+		}
+
+		// Preserve the ordering/filtering that base already computed (rating
+		// sort, min_rating, etc.) rather than whatever order the IN query
+		// happens to return.
+		results := make([]TutorSearchResult, len(ids))
+		for i, id := range ids {
+			results[i] = TutorSearchResult{UserDTO: userByID[id].ToDTO(), AverageRating: avgByID[id]}
+		}
+
+		json.NewEncoder(w).Encode(results)
+	}
+}
+
+// parseAvailabilityWindow parses the available_from/available_to query
+// params as RFC 3339 timestamps. Both, either, or neither may be set; if
+// only one is set it's treated as if neither was, since a window needs both
+// ends.
+func parseAvailabilityWindow(fromRaw, toRaw string) (from, to *time.Time, err error) {
+	if fromRaw == "" || toRaw == "" {
+		return nil, nil, nil
+	}
+
+	parsedFrom, err := time.Parse(time.RFC3339, fromRaw)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsedTo, err := time.Parse(time.RFC3339, toRaw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &parsedFrom, &parsedTo, nil
+}
+
+// AddAvailability adds a weekly availability slot to the tutor named by the
+// {id} mux var.
+func AddAvailability(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		userID := mux.Vars(r)["id"]
+
+		var user models.User
+		if err := db.First(&user, userID).Error; err != nil {
+			sendError("Error retrieving user", http.StatusNotFound, w)
+			return
+		}
+
+		var slot models.AvailabilitySlot
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&slot); err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+
+		if slot.DayOfWeek < 0 || slot.DayOfWeek > 6 || slot.StartMinute < 0 || slot.EndMinute > 24*60 || slot.StartMinute >= slot.EndMinute {
+			sendError("Invalid availability window", http.StatusBadRequest, w)
+			return
+		}
+
+		slot.TutorID = user.ID
+		if err := db.Create(&slot).Error; err != nil {
+			sendError("Error creating availability slot", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(slot)
+	}
+}
+
+// ListAvailability lists the availability slots belonging to the tutor
+// named by the {id} mux var.
+func ListAvailability(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		userID := mux.Vars(r)["id"]
+
+		var slots []models.AvailabilitySlot
+		if err := db.Where("tutor_id = ?", userID).Find(&slots).Error; err != nil {
+			sendError("Error retrieving availability", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(slots)
+	}
+}
+
+// DeleteAvailability removes the availability slot named by the {slotID}
+// mux var, provided it belongs to the tutor named by {id}.
+func DeleteAvailability(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		vars := mux.Vars(r)
+
+		var slot models.AvailabilitySlot
+		if err := db.Where("id = ? AND tutor_id = ?", vars["slotID"], vars["id"]).First(&slot).Error; err != nil {
+			sendError("Error retrieving availability slot", http.StatusNotFound, w)
+			return
+		}
+
+		if err := db.Delete(&slot).Error; err != nil {
+			sendError("Error deleting availability slot", http.StatusInternalServerError, w)
+			return
+		}
+
+		json.NewEncoder(w).Encode(slot)
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+)
+
+func TestSearchTutors(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	math := models.Subject{Name: "math"}
+	tx.Create(&math)
+
+	strongTutor := models.User{Username: "strong", Password: "x", IsTutor: true, Subjects: []models.Subject{math}}
+	weakTutor := models.User{Username: "weak", Password: "x", IsTutor: true, Subjects: []models.Subject{math}}
+	student := models.User{Username: "student", Password: "x"}
+	tx.Create(&strongTutor)
+	tx.Create(&weakTutor)
+	tx.Create(&student)
+
+	tx.Create(&models.Review{UserID: strongTutor.ID, Rating: 5})
+	tx.Create(&models.Review{UserID: strongTutor.ID, Rating: 3})
+	tx.Create(&models.Review{UserID: weakTutor.ID, Rating: 2})
+
+	// Monday, 14:00-16:00.
+	tx.Create(&models.AvailabilitySlot{TutorID: strongTutor.ID, DayOfWeek: 1, StartMinute: 14 * 60, EndMinute: 16 * 60})
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/tutors", SearchTutors(tx)).Methods("GET")
+
+	t.Run("filters by subject, excludes non-tutors", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/tutors?subject=math", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var results []TutorSearchResult
+		if err := json.Unmarshal(rr.Body.Bytes(), &results); err != nil {
+			t.Fatalf("decoding response: %s", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("got %v results want %v", len(results), 2)
+		}
+	})
+
+	t.Run("filters by min_rating", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/tutors?subject=math&min_rating=3", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var results []TutorSearchResult
+		json.Unmarshal(rr.Body.Bytes(), &results)
+		if len(results) != 1 || results[0].Username != "strong" {
+			t.Fatalf("got %+v, want only strong", results)
+		}
+	})
+
+	t.Run("sorts by rating_desc", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/tutors?subject=math&sort=rating_desc", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var results []TutorSearchResult
+		json.Unmarshal(rr.Body.Bytes(), &results)
+		if len(results) != 2 || results[0].Username != "strong" || results[1].Username != "weak" {
+			t.Fatalf("got %+v, want strong before weak", results)
+		}
+	})
+
+	t.Run("filters by availability window", func(t *testing.T) {
+		// 2024-01-15 is a Monday.
+		req := httptest.NewRequest("GET", "/api/tutors?available_from=2024-01-15T14:30:00Z&available_to=2024-01-15T15:30:00Z", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var results []TutorSearchResult
+		json.Unmarshal(rr.Body.Bytes(), &results)
+		if len(results) != 1 || results[0].Username != "strong" {
+			t.Fatalf("got %+v, want only strong", results)
+		}
+	})
+
+	t.Run("no match on the wrong day", func(t *testing.T) {
+		// 2024-01-16 is a Tuesday.
+		req := httptest.NewRequest("GET", "/api/tutors?available_from=2024-01-16T14:30:00Z&available_to=2024-01-16T15:30:00Z", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var results []TutorSearchResult
+		json.Unmarshal(rr.Body.Bytes(), &results)
+		if len(results) != 0 {
+			t.Fatalf("got %+v, want no results", results)
+		}
+	})
+
+	t.Run("never leaks password", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/tutors", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("got status %v want %v", status, http.StatusOK)
+		}
+		if bytes.Contains(rr.Body.Bytes(), []byte(`"password"`)) {
+			t.Errorf("response leaked password field: %s", rr.Body.String())
+		}
+	})
+}
+
+func TestAvailabilityCRUD(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	tutor := models.User{Username: "tutor", Password: "x", IsTutor: true}
+	tx.Create(&tutor)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users/{id}/availability", AddAvailability(tx)).Methods("POST")
+	r.HandleFunc("/api/users/{id}/availability", ListAvailability(tx)).Methods("GET")
+	r.HandleFunc("/api/users/{id}/availability/{slotID}", DeleteAvailability(tx)).Methods("DELETE")
+
+	path := "/api/users/" + strconv.FormatUint(uint64(tutor.ID), 10) + "/availability"
+
+	reqBody := []byte(`{"day_of_week":1,"start_minute":840,"end_minute":960}`)
+	req := httptest.NewRequest("POST", path, bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("AddAvailability: got status %v want %v, body %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	var created models.AvailabilitySlot
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	if created.ID == 0 {
+		t.Fatalf("expected created slot to have an ID")
+	}
+
+	req = httptest.NewRequest("GET", path, nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	var slots []models.AvailabilitySlot
+	json.Unmarshal(rr.Body.Bytes(), &slots)
+	if len(slots) != 1 {
+		t.Fatalf("got %v slots want %v", len(slots), 1)
+	}
+
+	deletePath := path + "/" + strconv.FormatUint(uint64(created.ID), 10)
+	req = httptest.NewRequest("DELETE", deletePath, nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("DeleteAvailability: got status %v want %v, body %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", path, nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	json.Unmarshal(rr.Body.Bytes(), &slots)
+	if len(slots) != 0 {
+		t.Fatalf("got %v slots want %v after delete", len(slots), 0)
+	}
+}
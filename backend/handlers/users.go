@@ -1,12 +1,13 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
-	"time"
+	"net/url"
 
 	"github.com/gorilla/mux"
 	"github.com/mayajenk/CEN3031/models"
@@ -23,13 +24,73 @@ func sendError(message string, status int, w http.ResponseWriter) {
 	json.NewEncoder(w).Encode(res)
 }
 
+const (
+	defaultUserListLimit = 25
+	maxUserListLimit     = 100
+)
+
+// userListFilterColumns are the User columns that can be filtered on
+// directly via an equality query param, e.g. ?is_tutor=true.
+var userListFilterColumns = map[string]string{
+	"username": "string",
+	"is_tutor": columnBool,
+	"is_admin": columnBool,
+}
+
+// userListSortColumns are the User columns that ?sort= may reference.
+var userListSortColumns = map[string]bool{
+	"id":         true,
+	"username":   true,
+	"created_at": true,
+}
+
+// userListQuery returns the base query for GetAllUsers after applying the
+// request's ?subject= join and whitelisted column filters, but before
+// sorting or pagination, so it can be reused for both the count and the
+// page itself.
+func userListQuery(db *gorm.DB, query url.Values) *gorm.DB {
+	q := db.Model(&models.User{})
+	if subject := query.Get("subject"); subject != "" {
+		q = q.Joins("JOIN user_subjects ON user_subjects.user_id = users.id").
+			Joins("JOIN subjects ON subjects.id = user_subjects.subject_id").
+			Where("subjects.name = ?", subject)
+	}
+	return applyFilters(q, query, userListFilterColumns)
+}
+
+// GetAllUsers lists users with pagination, subject/column filtering, and
+// sorting. Results are projected through models.UserDTO so password hashes
+// never reach the response.
 func GetAllUsers(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
+
+		query := r.URL.Query()
+		page := parsePagination(query, defaultUserListLimit, maxUserListLimit)
+
+		var total int64
+		if err := userListQuery(db, query).Count(&total).Error; err != nil {
+			sendError("Error counting users", http.StatusInternalServerError, w)
+			return
+		}
+
+		listQuery := applySort(userListQuery(db, query), query.Get("sort"), userListSortColumns)
+
 		var users []models.User
-		db.Model(&models.User{}).Preload("Subjects").Preload("Connections").Preload("Reviews").Find(&users)
+		err := listQuery.Preload("Subjects").Preload("Connections").Preload("Reviews").
+			Limit(page.limit).Offset(page.offset).Find(&users).Error
+		if err != nil {
+			sendError("Error retrieving users", http.StatusInternalServerError, w)
+			return
+		}
 
-		json.NewEncoder(w).Encode(users)
+		setPaginationHeaders(w, r, page, total)
+
+		dtos := make([]models.UserDTO, len(users))
+		for i, user := range users {
+			dtos[i] = user.ToDTO()
+		}
+		json.NewEncoder(w).Encode(dtos)
 	}
 }
 
@@ -84,7 +145,7 @@ func GetUser(db *gorm.DB) http.HandlerFunc {
 		if err != nil {
 			sendError("Error retrieving user", http.StatusUnauthorized, w)
 		} else {
-			json.NewEncoder(w).Encode(user)
+			json.NewEncoder(w).Encode(user.ToDTO())
 		}
 	}
 
@@ -95,14 +156,26 @@ func NewUser(db *gorm.DB) http.HandlerFunc {
 		w.Header().Add("Content-Type", "application/json")
 		fmt.Println("New User Endpoint Hit")
 
-		decoder := json.NewDecoder(r.Body)
-		decoder.DisallowUnknownFields()
-		var user models.User
-		err := decoder.Decode(&user)
+		decoded, validation, err := ValidateAndDecode[models.User](r, nil)
 		if err != nil {
 			sendError("Bad request format", http.StatusBadRequest, w)
 			return
 		}
+		if !validation.Valid() {
+			writeValidationError(validation, w)
+			return
+		}
+
+		// Only the allowlisted fields are copied onto the record that gets
+		// created - anything else in the body (most importantly IsAdmin)
+		// is discarded, so a self-registration request can't grant itself
+		// privileges it wasn't validated for.
+		user := models.User{
+			Username: decoded.Username,
+			Password: decoded.Password,
+			Email:    decoded.Email,
+			IsTutor:  decoded.IsTutor,
+		}
 
 		// Checking if a user is unique in the database
 		var existingUser models.User
@@ -111,18 +184,23 @@ func NewUser(db *gorm.DB) http.HandlerFunc {
 			sendError("Username already exists", http.StatusConflict, w)
 			return
 		} else if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			panic(result.Error)
+			sendError("Error checking username", http.StatusInternalServerError, w)
+			return
 		}
 
 		password, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
 		if err != nil {
-			panic("Failed to hash password")
+			sendError("Failed to hash password", http.StatusInternalServerError, w)
+			return
 		}
 		user.Password = string(password)
 
-		db.Create(&user)
+		if err := db.Create(&user).Error; err != nil {
+			sendError("Error creating user", http.StatusInternalServerError, w)
+			return
+		}
 
-		json.NewEncoder(w).Encode(user)
+		json.NewEncoder(w).Encode(user.ToDTO())
 	}
 }
 
@@ -135,53 +213,74 @@ func DeleteUser(db *gorm.DB) http.HandlerFunc {
 		db.First(&user, userID)
 		db.Delete(&user)
 
-		json.NewEncoder(w).Encode(user)
+		json.NewEncoder(w).Encode(user.ToDTO())
 	}
 }
 
+// updatableUserFields are the User fields UpdateUser treats as optional, so
+// a caller can update just the fields they're changing.
+var updatableUserFields = []string{"username", "password", "email", "is_tutor"}
+
 func UpdateUser(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
 		userID := mux.Vars(r)["id"]
+
 		var user models.User
-		db.First(&user, userID)
+		if err := db.First(&user, userID).Error; err != nil {
+			sendError("Error retrieving user", http.StatusNotFound, w)
+			return
+		}
 
-		var updatedUser models.User
-		decoder := json.NewDecoder(r.Body)
-		decoder.DisallowUnknownFields()
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
 
-		err := decoder.Decode(&updatedUser)
+		updatedUser, validation, err := ValidateAndDecode[models.User](r, updatableUserFields)
 		if err != nil {
-			panic(err)
+			sendError("Bad request format", http.StatusBadRequest, w)
+			return
+		}
+		if !validation.Valid() {
+			writeValidationError(validation, w)
+			return
 		}
 
-		if updatedUser.Password != user.Password {
+		if updatedUser.Password != "" {
 			password, err := bcrypt.GenerateFromPassword([]byte(updatedUser.Password), bcrypt.DefaultCost)
 			if err != nil {
-				panic("Failed to hash password")
+				sendError("Failed to hash password", http.StatusInternalServerError, w)
+				return
 			}
 			updatedUser.Password = string(password)
 		}
-		db.Model(&user).Updates(updatedUser)
-		w.Header().Add("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(user)
-	}
-}
 
-func UploadProfilePicture(db *gorm.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		userID := mux.Vars(r)["id"]
-		file, handler, err := r.FormFile("file")
-		if err != nil {
-			http.Error(w, "Error uploading file", http.StatusBadRequest)
-			return
+		// Only update columns the request actually named, restricted to
+		// updatableUserFields. This makes a present-but-zero value (e.g.
+		// is_tutor:false) take effect, which GORM's struct Updates would
+		// otherwise silently skip, and keeps fields like IsAdmin - which
+		// aren't in updatableUserFields - from being mass-assigned through
+		// this endpoint.
+		var raw map[string]json.RawMessage
+		json.Unmarshal(body, &raw)
+
+		var columns []string
+		for _, field := range updatableUserFields {
+			if _, present := raw[field]; present {
+				columns = append(columns, field)
+			}
 		}
-		defer file.Close()
-		filename := fmt.Sprintf("%s_%d_%s", userID, time.Now().Unix(), handler.Filename)
 
-		f, err := os.OpenFile("/uploads/"+filename, os.O_WRONLY|os.O_CREATE, 0666)
-		if err != nil {
-			http.Error(w, "Error saving file", http.StatusInternalServerError)
+		if len(columns) > 0 {
+			if err := db.Model(&user).Select(columns).Updates(updatedUser).Error; err != nil {
+				sendError("Error updating user", http.StatusInternalServerError, w)
+				return
+			}
 		}
-		defer f.Close()
+
+		json.NewEncoder(w).Encode(user.ToDTO())
 	}
 }
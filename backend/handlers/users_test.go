@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/models"
+)
+
+func TestGetAllUsersPaginationAndFiltering(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	for i := 0; i < 55; i++ {
+		user := models.User{
+			Username: "user" + strconv.Itoa(i),
+			Password: "secret",
+			IsTutor:  i%2 == 0,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			t.Fatalf("Error inserting test user: %s", err)
+		}
+	}
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users", GetAllUsers(tx)).Methods("GET")
+
+	t.Run("default page size", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got status %v want %v", rr.Code, http.StatusOK)
+		}
+		if got := rr.Header().Get("X-Total-Count"); got != "55" {
+			t.Errorf("X-Total-Count: got %v want %v", got, "55")
+		}
+
+		var users []models.UserDTO
+		if err := json.Unmarshal(rr.Body.Bytes(), &users); err != nil {
+			t.Fatalf("decoding response: %s", err)
+		}
+		if len(users) != defaultUserListLimit {
+			t.Errorf("got %v users want %v", len(users), defaultUserListLimit)
+		}
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users?limit=1000", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var users []models.UserDTO
+		json.Unmarshal(rr.Body.Bytes(), &users)
+		if len(users) > maxUserListLimit {
+			t.Errorf("got %v users, want at most %v", len(users), maxUserListLimit)
+		}
+	})
+
+	t.Run("filters by is_tutor", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users?is_tutor=true&limit=100", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get("X-Total-Count"); got != "28" {
+			t.Errorf("X-Total-Count: got %v want %v", got, "28")
+		}
+	})
+
+	t.Run("offset paginates", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users?limit=10&offset=50", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		var users []models.UserDTO
+		json.Unmarshal(rr.Body.Bytes(), &users)
+		if len(users) != 5 {
+			t.Errorf("got %v users want %v", len(users), 5)
+		}
+		if link := rr.Header().Get("Link"); link == "" {
+			t.Errorf("expected a Link header")
+		}
+	})
+
+	t.Run("never leaks password", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users?limit=100", nil)
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		if strings.Contains(rr.Body.String(), "secret") {
+			t.Errorf("response leaked password: %s", rr.Body.String())
+		}
+	})
+}
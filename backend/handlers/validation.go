@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// FieldError describes one invalid field found while validating a request
+// body.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidationResult collects every FieldError found while validating a
+// decoded request body. A zero-value ValidationResult is valid.
+type ValidationResult struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Valid reports whether no FieldErrors were recorded.
+func (v ValidationResult) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// writeValidationError sends result as a 400 JSON body of the form
+// {"errors":[{"field":"username","reason":"required"}, ...]}.
+func writeValidationError(result ValidationResult, w http.ResponseWriter) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(result)
+}
+
+var usernameCharset = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// fieldRules are the named checks a `validate` struct tag can reference,
+// beyond "required" (which ValidateAndDecode handles itself). Each takes
+// the field's decoded JSON value and reports whether it passes, and if not
+// why.
+var fieldRules = map[string]func(value any) (ok bool, reason string){
+	"username": func(value any) (bool, string) {
+		s, ok := value.(string)
+		if !ok {
+			return false, "must be a string"
+		}
+		if len(s) < 3 || len(s) > 32 {
+			return false, "length:3-32"
+		}
+		if !usernameCharset.MatchString(s) {
+			return false, "charset:alphanumeric_underscore"
+		}
+		return true, ""
+	},
+	"password": func(value any) (bool, string) {
+		s, ok := value.(string)
+		if !ok {
+			return false, "must be a string"
+		}
+		if len(s) < 8 {
+			return false, "min_length:8"
+		}
+		return true, ""
+	},
+	"bool": func(value any) (bool, string) {
+		if _, ok := value.(bool); !ok {
+			return false, "must be a boolean"
+		}
+		return true, ""
+	},
+	"email": func(value any) (bool, string) {
+		s, ok := value.(string)
+		if !ok {
+			return false, "must be a string"
+		}
+		if !emailPattern.MatchString(s) {
+			return false, "must be a valid email address"
+		}
+		return true, ""
+	},
+}
+
+// ValidateAndDecode decodes r's JSON body into a T and validates it against
+// the `validate` struct tags on T's fields (e.g. `validate:"required,username"`).
+// Fields named in allowMissing are exempt from "required" even if tagged
+// as such, which lets the same struct back both creation (everything
+// required) and partial-update (most fields optional) endpoints.
+//
+// The returned error is non-nil only when the body can't be read or isn't
+// a JSON object; anything wrong with individual fields - missing, unknown,
+// or failing a named rule (which is where wrong types are caught, so a
+// field without a validate tag isn't type-checked) - is reported in the
+// ValidationResult instead, so
+// callers always get a 400 with a precise field-level reason rather than a
+// generic "bad request".
+func ValidateAndDecode[T any](r *http.Request, allowMissing []string) (T, ValidationResult, error) {
+	var decoded T
+	var result ValidationResult
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return decoded, result, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return decoded, result, err
+	}
+
+	// Best-effort: populate whatever fields do decode cleanly. Fields with
+	// the wrong type are left at their zero value and, where they carry a
+	// validate tag, reported below by their rules, rather than aborting the
+	// whole decode.
+	json.Unmarshal(body, &decoded)
+
+	allowed := make(map[string]bool, len(allowMissing))
+	for _, field := range allowMissing {
+		allowed[field] = true
+	}
+
+	knownFields := make(map[string]bool)
+
+	t := reflect.TypeOf(decoded)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+		knownFields[jsonName] = true
+
+		rules := field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		ruleNames := strings.Split(rules, ",")
+
+		rawValue, present := raw[jsonName]
+		if !present {
+			if contains(ruleNames, "required") && !allowed[jsonName] {
+				result.Errors = append(result.Errors, FieldError{Field: jsonName, Reason: "required"})
+			}
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			result.Errors = append(result.Errors, FieldError{Field: jsonName, Reason: "invalid_type"})
+			continue
+		}
+
+		for _, rule := range ruleNames {
+			if rule == "required" {
+				continue
+			}
+			check, ok := fieldRules[rule]
+			if !ok {
+				continue
+			}
+			if ok, reason := check(value); !ok {
+				result.Errors = append(result.Errors, FieldError{Field: jsonName, Reason: reason})
+			}
+		}
+	}
+
+	for name := range raw {
+		if !knownFields[name] {
+			result.Errors = append(result.Errors, FieldError{Field: name, Reason: "unknown_field"})
+		}
+	}
+
+	return decoded, result, nil
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestNewUserValidationErrors(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users", NewUser(tx)).Methods("POST")
+
+	tests := []struct {
+		name   string
+		body   map[string]any
+		errors []FieldError
+	}{
+		{
+			name:   "missing username and password",
+			body:   map[string]any{"is_tutor": false},
+			errors: []FieldError{{Field: "username", Reason: "required"}, {Field: "password", Reason: "required"}},
+		},
+		{
+			name:   "password too short",
+			body:   map[string]any{"username": "validname", "password": "short", "is_tutor": false},
+			errors: []FieldError{{Field: "password", Reason: "min_length:8"}},
+		},
+		{
+			name:   "username bad charset",
+			body:   map[string]any{"username": "bad name!", "password": "longenough", "is_tutor": false},
+			errors: []FieldError{{Field: "username", Reason: "charset:alphanumeric_underscore"}},
+		},
+		{
+			name:   "is_tutor wrong type",
+			body:   map[string]any{"username": "validname", "password": "longenough", "is_tutor": "nope"},
+			errors: []FieldError{{Field: "is_tutor", Reason: "must be a boolean"}},
+		},
+		{
+			name:   "unknown field",
+			body:   map[string]any{"username": "validname", "password": "longenough", "is_tutor": false, "is_superuser": true},
+			errors: []FieldError{{Field: "is_superuser", Reason: "unknown_field"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(reqBody))
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("got status %v want %v, body %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+			}
+
+			var result ValidationResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatalf("decoding response: %s", err)
+			}
+
+			if len(result.Errors) != len(tc.errors) {
+				t.Fatalf("got errors %+v want %+v", result.Errors, tc.errors)
+			}
+			for _, want := range tc.errors {
+				found := false
+				for _, got := range result.Errors {
+					if got == want {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("expected error %+v in %+v", want, result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUserPartialUpdate(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users", NewUser(tx)).Methods("POST")
+	r.HandleFunc("/api/users/{id}", UpdateUser(tx)).Methods("PUT")
+
+	createBody, _ := json.Marshal(map[string]any{"username": "original", "password": "longenough", "is_tutor": false})
+	createReq := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(createBody))
+	createRR := httptest.NewRecorder()
+	r.ServeHTTP(createRR, createReq)
+
+	var created map[string]any
+	json.Unmarshal(createRR.Body.Bytes(), &created)
+	id := uint64(created["id"].(float64))
+
+	updateBody, _ := json.Marshal(map[string]any{"is_tutor": true})
+	updateReq := httptest.NewRequest("PUT", "/api/users/"+strconv.FormatUint(id, 10), bytes.NewBuffer(updateBody))
+	updateRR := httptest.NewRecorder()
+	r.ServeHTTP(updateRR, updateReq)
+
+	if updateRR.Code != http.StatusOK {
+		t.Fatalf("got status %v want %v, body %s", updateRR.Code, http.StatusOK, updateRR.Body.String())
+	}
+
+	var updated map[string]any
+	json.Unmarshal(updateRR.Body.Bytes(), &updated)
+	if updated["username"] != "original" {
+		t.Errorf("username should be unchanged: got %v", updated["username"])
+	}
+	if updated["is_tutor"] != true {
+		t.Errorf("is_tutor should be updated: got %v", updated["is_tutor"])
+	}
+
+	// A present-but-zero value must still take effect, not be silently
+	// skipped as an "unset" field.
+	demoteBody, _ := json.Marshal(map[string]any{"is_tutor": false})
+	demoteReq := httptest.NewRequest("PUT", "/api/users/"+strconv.FormatUint(id, 10), bytes.NewBuffer(demoteBody))
+	demoteRR := httptest.NewRecorder()
+	r.ServeHTTP(demoteRR, demoteReq)
+
+	var demoted map[string]any
+	json.Unmarshal(demoteRR.Body.Bytes(), &demoted)
+	if demoted["is_tutor"] != false {
+		t.Errorf("is_tutor should be demoted back to false: got %v", demoted["is_tutor"])
+	}
+}
+
+func TestUpdateUserCannotSelfPromoteToAdmin(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users", NewUser(tx)).Methods("POST")
+	r.HandleFunc("/api/users/{id}", UpdateUser(tx)).Methods("PUT")
+
+	createBody, _ := json.Marshal(map[string]any{"username": "wannabe", "password": "longenough", "is_tutor": false})
+	createReq := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(createBody))
+	createRR := httptest.NewRecorder()
+	r.ServeHTTP(createRR, createReq)
+
+	var created map[string]any
+	json.Unmarshal(createRR.Body.Bytes(), &created)
+	id := uint64(created["id"].(float64))
+
+	updateBody, _ := json.Marshal(map[string]any{"is_admin": true})
+	updateReq := httptest.NewRequest("PUT", "/api/users/"+strconv.FormatUint(id, 10), bytes.NewBuffer(updateBody))
+	updateRR := httptest.NewRecorder()
+	r.ServeHTTP(updateRR, updateReq)
+
+	var updated map[string]any
+	json.Unmarshal(updateRR.Body.Bytes(), &updated)
+	if updated["is_admin"] == true {
+		t.Errorf("is_admin must not be settable through UpdateUser: got %+v", updated)
+	}
+}
+
+func TestNewUserCannotSelfRegisterAsAdmin(t *testing.T) {
+	db := setupTestEnv()
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/users", NewUser(tx)).Methods("POST")
+
+	createBody, _ := json.Marshal(map[string]any{"username": "wannabe", "password": "longenough", "is_tutor": false, "is_admin": true})
+	createReq := httptest.NewRequest("POST", "/api/users", bytes.NewBuffer(createBody))
+	createRR := httptest.NewRecorder()
+	r.ServeHTTP(createRR, createReq)
+
+	if createRR.Code != http.StatusOK {
+		t.Fatalf("got status %v want %v, body %s", createRR.Code, http.StatusOK, createRR.Body.String())
+	}
+
+	var created map[string]any
+	json.Unmarshal(createRR.Body.Bytes(), &created)
+	if created["is_admin"] == true {
+		t.Errorf("is_admin must not be settable through NewUser: got %+v", created)
+	}
+	if _, leaked := created["password"]; leaked {
+		t.Errorf("response must not include password: got %+v", created)
+	}
+}
@@ -0,0 +1,29 @@
+package mail
+
+import "sync"
+
+// SentMessage records a single call to FakeSender.Send.
+type SentMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// FakeSender is a Sender that records messages instead of delivering them,
+// for use in tests.
+type FakeSender struct {
+	mu   sync.Mutex
+	Sent []SentMessage
+}
+
+// NewFakeSender returns an empty FakeSender.
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+func (f *FakeSender) Send(to, subject, body string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Sent = append(f.Sent, SentMessage{To: to, Subject: subject, Body: body})
+	return nil
+}
@@ -0,0 +1,9 @@
+// Package mail provides a pluggable interface for sending transactional
+// email, with an SMTP implementation for production and an in-memory fake
+// for tests.
+package mail
+
+// Sender dispatches a single plain-text email.
+type Sender interface {
+	Send(to, subject, body string) error
+}
@@ -0,0 +1,23 @@
+package mail
+
+import (
+	"fmt"
+	"net/smtp"
+)
+
+// SMTPSender sends email through an SMTP relay.
+type SMTPSender struct {
+	Addr string // host:port of the SMTP server
+	From string
+	Auth smtp.Auth
+}
+
+// NewSMTPSender builds an SMTPSender targeting the given SMTP server.
+func NewSMTPSender(addr, from string, auth smtp.Auth) *SMTPSender {
+	return &SMTPSender{Addr: addr, From: from, Auth: auth}
+}
+
+func (s *SMTPSender) Send(to, subject, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", s.From, to, subject, body)
+	return smtp.SendMail(s.Addr, s.Auth, s.From, []string{to}, []byte(msg))
+}
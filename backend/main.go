@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/smtp"
 	"os"
 	"time"
 
 	"github.com/glebarez/sqlite"
+	"github.com/mayajenk/CEN3031/mail"
 	"github.com/mayajenk/CEN3031/models"
 	"github.com/wader/gormstore/v2"
 	"gorm.io/gorm"
@@ -25,16 +27,25 @@ func main() {
 	}
 
 	store := gormstore.New(sessionDB, []byte(os.Getenv("SESSION_KEY")))
+	store.SessionOpts.HttpOnly = true
+	store.SessionOpts.Secure = true
+	store.SessionOpts.SameSite = http.SameSiteStrictMode
 
 	// Periodically clean up sessions
 	quit := make(chan struct{})
 	go store.PeriodicCleanup(1*time.Hour, quit)
 
-	db.AutoMigrate(&models.User{})
+	db.AutoMigrate(&models.User{}, &models.Subject{}, &models.Connection{}, &models.Review{}, &models.PasswordResetToken{}, &models.AvailabilitySlot{})
+
+	sender := mail.NewSMTPSender(
+		os.Getenv("SMTP_ADDR"),
+		os.Getenv("SMTP_FROM"),
+		smtp.PlainAuth("", os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_HOST")),
+	)
 
 	host := "0.0.0.0:8080"
 	fmt.Println("Serving on " + host)
-	if err := http.ListenAndServe(host, httpHandler(store, db)); err != nil {
+	if err := http.ListenAndServe(host, httpHandler(store, db, sessionDB, sender)); err != nil {
 		log.Fatalf("Failed to listen on %s: %v", host, err)
 	}
 }
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// AvailabilitySlot is a weekly-recurring window of time during which a
+// tutor is available, e.g. "Mondays from 14:00 to 16:00". Times are stored
+// as minutes since midnight (UTC) rather than as timestamps, since the
+// schedule repeats every week rather than occurring on a specific date.
+type AvailabilitySlot struct {
+	gorm.Model
+	TutorID     uint `json:"tutor_id"`
+	DayOfWeek   int  `json:"day_of_week"` // 0 = Sunday, matching time.Weekday
+	StartMinute int  `json:"start_minute"`
+	EndMinute   int  `json:"end_minute"`
+}
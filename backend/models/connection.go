@@ -0,0 +1,10 @@
+package models
+
+import "gorm.io/gorm"
+
+// Connection links a student to a tutor once a tutoring relationship is formed.
+type Connection struct {
+	gorm.Model
+	UserID          uint `json:"user_id"`
+	ConnectedUserID uint `json:"connected_user_id"`
+}
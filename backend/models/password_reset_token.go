@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken is a short-lived, single-use credential issued by
+// RequestPasswordReset. Only the hash of the token is ever persisted; the
+// raw token is mailed to the user and never stored.
+type PasswordResetToken struct {
+	gorm.Model
+	UserID    uint      `json:"user_id"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+}
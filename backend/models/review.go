@@ -0,0 +1,11 @@
+package models
+
+import "gorm.io/gorm"
+
+// Review is a student's rating of a tutor after a session.
+type Review struct {
+	gorm.Model
+	UserID  uint   `json:"user_id"`
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
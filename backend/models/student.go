@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// Student is the student-facing view of a User, shaped for responses that
+// only make sense for students (tutor connections).
+type Student struct {
+	gorm.Model
+	Username    string       `json:"username"`
+	IsTutor     bool         `json:"is_tutor"`
+	Connections []Connection `json:"connections,omitempty"`
+}
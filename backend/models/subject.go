@@ -0,0 +1,9 @@
+package models
+
+import "gorm.io/gorm"
+
+// Subject is a topic a tutor can offer or a student can request help with.
+type Subject struct {
+	gorm.Model
+	Name string `json:"name" gorm:"uniqueIndex"`
+}
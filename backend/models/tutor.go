@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// Tutor is the tutor-facing view of a User, shaped for responses that only
+// make sense for tutors (subjects offered, reviews received).
+type Tutor struct {
+	gorm.Model
+	Username string    `json:"username"`
+	IsTutor  bool      `json:"is_tutor"`
+	Subjects []Subject `json:"subjects,omitempty"`
+	Reviews  []Review  `json:"reviews,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "gorm.io/gorm"
+
+// Role is a coarse permission tier derived from a User's IsTutor/IsAdmin
+// flags, used by the authorization middleware in handlers.
+type Role string
+
+const (
+	RoleStudent Role = "student"
+	RoleTutor   Role = "tutor"
+	RoleAdmin   Role = "admin"
+)
+
+// User is the core account record shared by tutors and students.
+type User struct {
+	gorm.Model
+	Username          string             `json:"username" gorm:"uniqueIndex" validate:"required,username"`
+	Password          string             `json:"password" validate:"required,password"`
+	Email             string             `json:"email" validate:"email"`
+	IsTutor           bool               `json:"is_tutor" validate:"required,bool"`
+	IsAdmin           bool               `json:"is_admin"`
+	ProfilePictureURL string             `json:"profile_picture_url"` // filename under the profile picture upload dir, not a full URL
+	Subjects          []Subject          `json:"subjects,omitempty" gorm:"many2many:user_subjects;"`
+	Connections       []Connection       `json:"connections,omitempty"`
+	Reviews           []Review           `json:"reviews,omitempty"`
+	Availability      []AvailabilitySlot `json:"availability,omitempty" gorm:"foreignKey:TutorID"`
+}
+
+// Role reports the user's highest applicable permission tier. IsAdmin takes
+// precedence over IsTutor.
+func (u User) Role() Role {
+	switch {
+	case u.IsAdmin:
+		return RoleAdmin
+	case u.IsTutor:
+		return RoleTutor
+	default:
+		return RoleStudent
+	}
+}
+
+// UserDTO is the externally-visible projection of a User. It drops Password
+// so list/detail endpoints can't leak password hashes by forgetting to
+// scrub them.
+type UserDTO struct {
+	ID                uint               `json:"id"`
+	Username          string             `json:"username"`
+	Email             string             `json:"email"`
+	IsTutor           bool               `json:"is_tutor"`
+	IsAdmin           bool               `json:"is_admin"`
+	ProfilePictureURL string             `json:"profile_picture_url"`
+	Subjects          []Subject          `json:"subjects,omitempty"`
+	Connections       []Connection       `json:"connections,omitempty"`
+	Reviews           []Review           `json:"reviews,omitempty"`
+	Availability      []AvailabilitySlot `json:"availability,omitempty"`
+}
+
+// ToDTO projects u into its externally-visible representation.
+func (u User) ToDTO() UserDTO {
+	return UserDTO{
+		ID:                u.ID,
+		Username:          u.Username,
+		Email:             u.Email,
+		IsTutor:           u.IsTutor,
+		IsAdmin:           u.IsAdmin,
+		ProfilePictureURL: u.ProfilePictureURL,
+		Subjects:          u.Subjects,
+		Connections:       u.Connections,
+		Reviews:           u.Reviews,
+		Availability:      u.Availability,
+	}
+}
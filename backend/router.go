@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mayajenk/CEN3031/handlers"
+	"github.com/mayajenk/CEN3031/mail"
+	"github.com/wader/gormstore/v2"
+	"gorm.io/gorm"
+)
+
+// httpHandler builds the top-level router for the application.
+func httpHandler(store *gormstore.Store, db *gorm.DB, sessionDB *gorm.DB, sender mail.Sender) http.Handler {
+	r := mux.NewRouter()
+	r.Use(handlers.RequireCSRFToken)
+
+	requireAuth := handlers.RequireAuth(store, db)
+	requireSelfOrAdmin := handlers.RequireSelfOrAdmin(store, db)
+
+	r.HandleFunc("/api/users", handlers.GetAllUsers(db)).Methods("GET")
+	r.HandleFunc("/api/users", handlers.NewUser(db)).Methods("POST")
+	r.HandleFunc("/api/users/me", handlers.GetUserFromSession(store, db)).Methods("GET")
+	r.HandleFunc("/api/users/{id}", handlers.GetUser(db)).Methods("GET")
+	r.Handle("/api/users/{id}", requireSelfOrAdmin(handlers.UpdateUser(db))).Methods("PUT")
+	r.Handle("/api/users/{id}", requireSelfOrAdmin(handlers.DeleteUser(db))).Methods("DELETE")
+	r.Handle("/api/users/{id}/subjects", requireSelfOrAdmin(handlers.AddSubject(db))).Methods("POST")
+	r.Handle("/api/users/{id}/picture", requireSelfOrAdmin(handlers.UploadProfilePicture(db))).Methods("POST")
+	r.HandleFunc("/api/users/{id}/picture", handlers.GetProfilePicture(db)).Methods("GET")
+
+	r.HandleFunc("/api/tutors", handlers.SearchTutors(db)).Methods("GET")
+	r.Handle("/api/users/{id}/availability", requireSelfOrAdmin(handlers.AddAvailability(db))).Methods("POST")
+	r.HandleFunc("/api/users/{id}/availability", handlers.ListAvailability(db)).Methods("GET")
+	r.Handle("/api/users/{id}/availability/{slotID}", requireSelfOrAdmin(handlers.DeleteAvailability(db))).Methods("DELETE")
+
+	r.HandleFunc("/api/login", handlers.Login(store, db)).Methods("POST")
+	r.Handle("/api/logout", requireAuth(handlers.Logout(store))).Methods("POST")
+
+	r.HandleFunc("/api/password-reset", handlers.RequestPasswordReset(db, sender)).Methods("POST")
+	r.HandleFunc("/api/password-reset", handlers.ValidatePasswordResetToken(db)).Methods("GET")
+	r.HandleFunc("/api/password-reset/confirm", handlers.ConfirmPasswordReset(store, db, sessionDB)).Methods("POST")
+
+	return r
+}